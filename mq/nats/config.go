@@ -0,0 +1,64 @@
+package natsmq
+
+import (
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/stan.go"
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// Config bundles the connection parameters shared by every constructor in
+// this package (NewConn, NewEncodedConn, NewStanConn, ...). Callers fill in
+// the fields relevant to the transport they're connecting to and leave the
+// rest zero-valued.
+type Config struct {
+	Broker    string
+	ClientId  string
+	ClusterId string
+
+	NatsConn []nats.Option
+	StanConn []stan.Option
+
+	// PoolSize is the number of independent connections NewConnPool dials.
+	// Defaults to 1 when unset.
+	PoolSize int
+
+	// Encoder names the codec NewEncodedConn registers messages with, e.g.
+	// nats.JSON_ENCODER or nats.GOB_ENCODER, or the name of a custom codec
+	// registered beforehand via nats.RegisterEncoder (for protobuf, say).
+	// Defaults to nats.JSON_ENCODER when empty.
+	Encoder string
+
+	AckTimeout time.Duration
+
+	// MetricsRegistry, when set, makes NewConn, NewEncodedConn, NewStanConn,
+	// and NewJetStreamConn register Prometheus collectors for publishes,
+	// acks, and connection state. Left nil, no metrics are registered.
+	// NewConnPool does not consult it today; use its Healthz/Stats instead.
+	MetricsRegistry prometheus.Registerer
+
+	// JetStream configures the stream/consumer a JetStreamConn declares on
+	// startup. Left nil, NewJetStreamConn connects without declaring a
+	// stream so callers can manage one out-of-band.
+	JetStream *JetStreamConfig
+
+	Logger *zap.SugaredLogger
+	Tracer opentracing.Tracer
+}
+
+// JetStreamConfig declares the stream a JetStreamConn publishes into and the
+// redelivery policy its consumers use. Zero-valued AckWait, MaxDeliver and
+// MaxAckPending fall back to the DefaultJetStream* constants; an empty
+// Durable falls back to the client ID.
+type JetStreamConfig struct {
+	Stream   string
+	Subjects []string
+	Durable  string
+
+	AckWait       time.Duration
+	MaxDeliver    int
+	MaxAckPending int
+}
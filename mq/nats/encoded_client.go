@@ -0,0 +1,51 @@
+package natsmq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// EncodedClient wraps a *nats.EncodedConn with typed request/reply and
+// queue-subscribe helpers, so services can do RPC over NATS without
+// reaching into the raw EncodedConn themselves.
+type EncodedClient struct {
+	conn *nats.EncodedConn
+}
+
+// NewEncodedClient wraps an already-established EncodedConn, such as one
+// returned by NewEncodedConn.
+func NewEncodedClient(conn *nats.EncodedConn) *EncodedClient {
+	return &EncodedClient{conn: conn}
+}
+
+// Request publishes req to subject, encoded with the client's codec, and
+// decodes the reply into reply, bounded by timeout or ctx's deadline,
+// whichever is sooner.
+func (ec *EncodedClient) Request(ctx context.Context, subject string, req, reply interface{}, timeout time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < timeout {
+			timeout = remaining
+		}
+	}
+
+	if err := ec.conn.Request(subject, req, reply, timeout); err != nil {
+		return fmt.Errorf("nats request %q: %w", subject, err)
+	}
+	return nil
+}
+
+// QueueSubscribe decodes every message on subject and invokes handlerFunc,
+// load-balancing delivery across every subscriber sharing group. See
+// (*nats.EncodedConn).QueueSubscribe for the handlerFunc signatures it
+// accepts.
+func (ec *EncodedClient) QueueSubscribe(subject, group string, handlerFunc interface{}) (*nats.Subscription, error) {
+	return ec.conn.QueueSubscribe(subject, group, handlerFunc)
+}
+
+// Close closes the underlying connection without draining it first.
+func (ec *EncodedClient) Close() {
+	ec.conn.Close()
+}
@@ -0,0 +1,303 @@
+package natsmq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/opentracing/opentracing-go"
+	"go.uber.org/zap"
+
+	"github.com/rovergulf/utils/mq/nats/pubsub"
+)
+
+const (
+	DefaultJetStreamAckWait       = 30 * time.Second
+	DefaultJetStreamMaxDeliver    = 5
+	DefaultJetStreamMaxAckPending = 1000
+)
+
+// JetStreamConn wraps a nats.JetStreamContext and mirrors the ergonomics of
+// StanConn (SendMessage, SendAsyncMessage, DefaultAckHandler) so callers can
+// migrate off nats-streaming, which is EOL, without rewriting call sites.
+// Unlike StanConn, delivery is backed by JetStream's ack/redelivery policy
+// (AckWait, MaxDeliver, MaxAckPending) rather than STAN's.
+type JetStreamConn struct {
+	clientId string
+	nc       *nats.Conn
+	js       nats.JetStreamContext
+	cfg      *JetStreamConfig
+	tracer   opentracing.Tracer
+	logger   *zap.SugaredLogger
+	metrics  *metrics
+}
+
+func NewJetStreamConn(c *Config) (*JetStreamConn, error) {
+	lg := c.Logger.Named("jetstream")
+
+	nc, err := NewConn(c)
+	if err != nil {
+		lg.Errorw("Failed to set nats server connection", "broker", c.Broker, "err", err)
+		return nil, err
+	}
+
+	js, err := nc.JetStream()
+	if err != nil {
+		lg.Errorw("Failed to get JetStream context", "err", err)
+		return nil, err
+	}
+
+	cfg := c.JetStream
+	if cfg == nil {
+		cfg = &JetStreamConfig{}
+	}
+	if cfg.AckWait <= 0 {
+		cfg.AckWait = DefaultJetStreamAckWait
+	}
+	if cfg.MaxDeliver <= 0 {
+		cfg.MaxDeliver = DefaultJetStreamMaxDeliver
+	}
+	if cfg.MaxAckPending <= 0 {
+		cfg.MaxAckPending = DefaultJetStreamMaxAckPending
+	}
+
+	jsc := &JetStreamConn{
+		clientId: c.ClientId,
+		nc:       nc,
+		js:       js,
+		cfg:      cfg,
+		tracer:   c.Tracer,
+		logger:   lg,
+		metrics:  newMetrics(c.MetricsRegistry, "jetstream"),
+	}
+
+	if cfg.Stream != "" {
+		if err := jsc.ensureStream(); err != nil {
+			lg.Errorw("Failed to declare stream", "stream", cfg.Stream, "err", err)
+			return nil, err
+		}
+	}
+
+	lg.Infow("Initialized JetStream conn",
+		"broker", c.Broker, "stream", cfg.Stream, "client_id", c.ClientId)
+	return jsc, nil
+}
+
+func (jc *JetStreamConn) ensureStream() error {
+	if _, err := jc.js.StreamInfo(jc.cfg.Stream); err == nil {
+		return nil
+	} else if err != nats.ErrStreamNotFound {
+		return err
+	}
+
+	_, err := jc.js.AddStream(&nats.StreamConfig{
+		Name:     jc.cfg.Stream,
+		Subjects: jc.cfg.Subjects,
+	})
+	return err
+}
+
+func (jc *JetStreamConn) Stop() {
+	if jc.nc != nil {
+		jc.logger.Debugw("Closing connection", "client_id", jc.clientId)
+		jc.nc.Close()
+	}
+}
+
+// Healthz reports a non-nil error when the underlying NATS connection is
+// down, suitable for wiring into an HTTP readiness probe.
+func (jc *JetStreamConn) Healthz() error {
+	if jc.nc == nil || !jc.nc.IsConnected() {
+		return fmt.Errorf("jetstream: not connected")
+	}
+	return nil
+}
+
+func (jc *JetStreamConn) DefaultAckHandler(ack *nats.PubAck, err error) {
+	if err != nil {
+		jc.logger.Errorw("Error publishing message", "err", err)
+		return
+	}
+	jc.logger.Infow("Received ack for message", "stream", ack.Stream, "seq", ack.Sequence)
+}
+
+// traceHeader builds a native nats.Header carrying a span for operation,
+// started from ctx, using jc.tracer. NATS 2.2+ (which
+// JetStream requires) carries headers natively, so unlike StanConn there's
+// no need to fold them into the JSON envelope.
+func (jc *JetStreamConn) traceHeader(ctx context.Context, operation string) (nats.Header, opentracing.Span) {
+	if jc.tracer == nil {
+		return nil, nil
+	}
+
+	msg := &pubsub.Message{Publisher: jc.clientId}
+	span := injectSpan(ctx, jc.tracer, operation, msg)
+	if len(msg.Headers) == 0 {
+		return nil, span
+	}
+
+	header := nats.Header{}
+	for k, v := range msg.Headers {
+		header.Set(k, v)
+	}
+	return header, span
+}
+
+// SendMessage publishes data to channel.
+//
+// Deprecated: use SendMessageContext so the publish can be linked into a
+// caller's distributed trace.
+func (jc *JetStreamConn) SendMessage(channel string, data interface{}) {
+	jc.SendMessageContext(context.Background(), channel, data)
+}
+
+func (jc *JetStreamConn) SendMessageContext(ctx context.Context, channel string, data interface{}) {
+	if jc.js == nil {
+		return
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		jc.logger.Errorf("Unable to marshal data: %s", err)
+		return
+	}
+
+	header, span := jc.traceHeader(ctx, "jetstream.publish "+channel)
+	if span != nil {
+		defer span.Finish()
+	}
+
+	start := time.Now()
+	ack, err := jc.js.PublishMsg(&nats.Msg{Subject: channel, Data: payload, Header: header})
+	jc.metrics.observePublish(channel, start, err)
+	if err == nil {
+		jc.metrics.observeAck(channel, start)
+	}
+	jc.DefaultAckHandler(ack, err)
+}
+
+// SendAsyncMessage publishes data to channel without waiting for the ack.
+//
+// Deprecated: use SendAsyncMessageContext so the publish can be linked into
+// a caller's distributed trace.
+func (jc *JetStreamConn) SendAsyncMessage(channel string, data interface{}) {
+	jc.SendAsyncMessageContext(context.Background(), channel, data)
+}
+
+func (jc *JetStreamConn) SendAsyncMessageContext(ctx context.Context, channel string, data interface{}) {
+	if jc.js == nil {
+		return
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		jc.logger.Errorf("Unable to marshal data: %s", err)
+		return
+	}
+
+	header, span := jc.traceHeader(ctx, "jetstream.publish_async "+channel)
+	if span != nil {
+		defer span.Finish()
+	}
+
+	start := time.Now()
+	future, err := jc.js.PublishMsgAsync(&nats.Msg{Subject: channel, Data: payload, Header: header})
+	jc.metrics.observePublish(channel, start, err)
+	if err != nil {
+		jc.logger.Errorw("Error publishing", "chan", channel, "client_id", jc.clientId, "err", err)
+		return
+	}
+
+	go func() {
+		select {
+		case ack := <-future.Ok():
+			jc.metrics.observeAck(channel, start)
+			jc.DefaultAckHandler(ack, nil)
+		case err := <-future.Err():
+			jc.DefaultAckHandler(nil, err)
+		}
+	}()
+}
+
+// durableName returns cfg.Durable, falling back to the client ID when unset.
+func (jc *JetStreamConn) durableName() string {
+	if jc.cfg.Durable != "" {
+		return jc.cfg.Durable
+	}
+	return jc.clientId
+}
+
+// traceHandler wraps handler so, when a tracer is configured, each delivery
+// starts a child span from the message's native headers before invoking it.
+func (jc *JetStreamConn) traceHandler(channel string, handler nats.MsgHandler) nats.MsgHandler {
+	if jc.tracer == nil {
+		return handler
+	}
+
+	return func(m *nats.Msg) {
+		headers := make(map[string]string, len(m.Header))
+		for k := range m.Header {
+			headers[k] = m.Header.Get(k)
+		}
+
+		_, span := extractSpan(context.Background(), jc.tracer, "jetstream.subscribe "+channel, &pubsub.Message{Headers: headers})
+		if span != nil {
+			defer span.Finish()
+		}
+		handler(m)
+	}
+}
+
+// Subscribe creates a durable push consumer bound to cfg.Durable (falling
+// back to the client ID when unset), following the AckWait/MaxDeliver/
+// MaxAckPending policy declared on the Config. Pass a non-empty group to
+// load-balance delivery across a queue of consumers. When a tracer is
+// configured, each delivery starts a child span from the message's native
+// headers before invoking handler.
+func (jc *JetStreamConn) Subscribe(channel, group string, handler nats.MsgHandler) (*nats.Subscription, error) {
+	opts := []nats.SubOpt{
+		nats.Durable(jc.durableName()),
+		nats.AckWait(jc.cfg.AckWait),
+		nats.MaxDeliver(jc.cfg.MaxDeliver),
+		nats.MaxAckPending(jc.cfg.MaxAckPending),
+		nats.ManualAck(),
+	}
+
+	wrapped := jc.traceHandler(channel, handler)
+
+	if group != "" {
+		return jc.js.QueueSubscribe(channel, group, wrapped, opts...)
+	}
+	return jc.js.Subscribe(channel, wrapped, opts...)
+}
+
+// PullSubscribe creates a durable pull consumer bound to cfg.Durable
+// (falling back to the client ID when unset), following the same
+// AckWait/MaxDeliver/MaxAckPending policy as Subscribe. Unlike Subscribe,
+// nothing is delivered until the caller pulls a batch with Fetch.
+func (jc *JetStreamConn) PullSubscribe(channel string) (*nats.Subscription, error) {
+	return jc.js.PullSubscribe(channel, jc.durableName(),
+		nats.AckWait(jc.cfg.AckWait),
+		nats.MaxDeliver(jc.cfg.MaxDeliver),
+		nats.MaxAckPending(jc.cfg.MaxAckPending),
+	)
+}
+
+// Fetch pulls up to batch messages from sub (a subscription returned by
+// PullSubscribe), waiting up to timeout, and invokes handler for each. When
+// a tracer is configured, each delivery starts a child span from the
+// message's native headers before invoking handler, same as Subscribe.
+func (jc *JetStreamConn) Fetch(channel string, sub *nats.Subscription, batch int, timeout time.Duration, handler nats.MsgHandler) error {
+	msgs, err := sub.Fetch(batch, nats.MaxWait(timeout))
+	if err != nil {
+		return err
+	}
+
+	wrapped := jc.traceHandler(channel, handler)
+	for _, m := range msgs {
+		wrapped(m)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package natsmq
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metricsCache keys the collectors already registered for a given
+// (registry, subsystem) pair, so creating a second StanConn/JetStreamConn
+// against the same Config.MetricsRegistry reuses the existing counters
+// instead of tripping Prometheus's duplicate-registration panic.
+var (
+	metricsCacheMu sync.Mutex
+	metricsCache   = map[metricsCacheKey]*metrics{}
+)
+
+type metricsCacheKey struct {
+	reg       prometheus.Registerer
+	subsystem string
+}
+
+// metrics bundles the Prometheus collectors shared by every connection type
+// in this package. It's nil whenever Config.MetricsRegistry isn't set, so
+// every call site on the hot path must nil-check it before use.
+type metrics struct {
+	messagesPublished *prometheus.CounterVec
+	publishErrors     *prometheus.CounterVec
+	publishLatency    *prometheus.HistogramVec
+	ackLatency        *prometheus.HistogramVec
+
+	reconnects  prometheus.Counter
+	disconnects prometheus.Counter
+	connected   prometheus.Gauge
+}
+
+// newMetrics registers a set of collectors for subsystem (e.g. "stan",
+// "jetstream") against reg, or returns the set already registered for that
+// (reg, subsystem) pair. It returns nil if reg is nil.
+func newMetrics(reg prometheus.Registerer, subsystem string) *metrics {
+	if reg == nil {
+		return nil
+	}
+
+	key := metricsCacheKey{reg: reg, subsystem: subsystem}
+
+	metricsCacheMu.Lock()
+	defer metricsCacheMu.Unlock()
+	if m, ok := metricsCache[key]; ok {
+		return m
+	}
+
+	m := &metrics{
+		messagesPublished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "natsmq",
+			Subsystem: subsystem,
+			Name:      "messages_published_total",
+			Help:      "Total messages successfully published.",
+		}, []string{"channel"}),
+		publishErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "natsmq",
+			Subsystem: subsystem,
+			Name:      "publish_errors_total",
+			Help:      "Total publish attempts that returned an error.",
+		}, []string{"channel"}),
+		publishLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "natsmq",
+			Subsystem: subsystem,
+			Name:      "publish_latency_seconds",
+			Help:      "Time spent in the publish call itself.",
+		}, []string{"channel"}),
+		ackLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "natsmq",
+			Subsystem: subsystem,
+			Name:      "ack_latency_seconds",
+			Help:      "Time from publish to the broker ack (STAN/JetStream only).",
+		}, []string{"channel"}),
+		reconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "natsmq",
+			Subsystem: subsystem,
+			Name:      "reconnects_total",
+			Help:      "Total reconnects observed on the underlying NATS connection.",
+		}),
+		disconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "natsmq",
+			Subsystem: subsystem,
+			Name:      "disconnects_total",
+			Help:      "Total disconnects observed on the underlying NATS connection.",
+		}),
+		connected: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "natsmq",
+			Subsystem: subsystem,
+			Name:      "connected",
+			Help:      "1 if the underlying NATS connection is currently connected, 0 otherwise.",
+		}),
+	}
+
+	reg.MustRegister(m.messagesPublished, m.publishErrors, m.publishLatency,
+		m.ackLatency, m.reconnects, m.disconnects, m.connected)
+
+	metricsCache[key] = m
+	return m
+}
+
+// observePublish records the outcome and latency of a single publish call.
+func (m *metrics) observePublish(channel string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+
+	m.publishLatency.WithLabelValues(channel).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.publishErrors.WithLabelValues(channel).Inc()
+		return
+	}
+	m.messagesPublished.WithLabelValues(channel).Inc()
+}
+
+// observeAck records the time elapsed between a publish and its ack.
+func (m *metrics) observeAck(channel string, since time.Time) {
+	if m == nil {
+		return
+	}
+	m.ackLatency.WithLabelValues(channel).Observe(time.Since(since).Seconds())
+}
+
+// onConnect, onReconnect, onDisconnect, and onClosed update connection-state
+// bookkeeping. They're folded into setupDefaultNatsConnOptions's own
+// ConnectHandler/ReconnectHandler/DisconnectErrHandler/ClosedHandler calls
+// rather than registered as separate nats.Options: nats.go keeps only the
+// last handler set per event, so a second, independent registration would
+// silently replace (not supplement) the logging callbacks already there.
+func (m *metrics) onConnect() {
+	if m == nil {
+		return
+	}
+	m.connected.Set(1)
+}
+
+func (m *metrics) onReconnect() {
+	if m == nil {
+		return
+	}
+	m.reconnects.Inc()
+	m.connected.Set(1)
+}
+
+func (m *metrics) onDisconnect() {
+	if m == nil {
+		return
+	}
+	m.disconnects.Inc()
+	m.connected.Set(0)
+}
+
+func (m *metrics) onClosed() {
+	if m == nil {
+		return
+	}
+	m.connected.Set(0)
+}
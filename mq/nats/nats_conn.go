@@ -6,7 +6,12 @@ import (
 	"time"
 )
 
-func setupDefaultNatsConnOptions(lg *zap.SugaredLogger, opts []nats.Option) []nats.Option {
+// setupDefaultNatsConnOptions attaches the default reconnect policy and
+// logging callbacks. m may be nil (metrics disabled); when set, its
+// bookkeeping is folded into the same callbacks rather than registered as
+// separate options, since nats.go only keeps the last handler set for a
+// given event.
+func setupDefaultNatsConnOptions(lg *zap.SugaredLogger, opts []nats.Option, m *metrics) []nats.Option {
 	totalWait := 10 * time.Minute
 	reconnectDelay := 10 * time.Second
 
@@ -14,22 +19,30 @@ func setupDefaultNatsConnOptions(lg *zap.SugaredLogger, opts []nats.Option) []na
 	opts = append(opts, nats.MaxReconnects(int(totalWait/reconnectDelay)))
 	opts = append(opts, nats.DisconnectErrHandler(func(nc *nats.Conn, e error) {
 		lg.Warnf("Disconnected due: %s. Will attempt reconnects for %.0fm", e, totalWait.Minutes())
+		m.onDisconnect()
 	}))
 	opts = append(opts, nats.ReconnectHandler(func(nc *nats.Conn) {
 		lg.Warnf("Successfullly reconnected [%s]", nc.ConnectedUrl())
+		m.onReconnect()
 	}))
 	opts = append(opts, nats.ClosedHandler(func(nc *nats.Conn) {
 		lg.Warnf("NATS connection closed: %v", nc.LastError())
+		m.onClosed()
 	}))
 	opts = append(opts, nats.ErrorHandler(func(nc *nats.Conn, _ *nats.Subscription, err error) {
 		lg.Errorf("Connection error: %s", err)
 	}))
+	if m != nil {
+		opts = append(opts, nats.ConnectHandler(func(nc *nats.Conn) {
+			m.onConnect()
+		}))
+	}
 
 	return opts
 }
 
 func NewConn(c *Config) (*nats.Conn, error) {
-	c.NatsConn = setupDefaultNatsConnOptions(c.Logger.Named("nats"), c.NatsConn)
+	c.NatsConn = setupDefaultNatsConnOptions(c.Logger.Named("nats"), c.NatsConn, newMetrics(c.MetricsRegistry, "nats"))
 
 	nc, err := nats.Connect(c.Broker, c.NatsConn...)
 	if err != nil {
@@ -45,11 +58,16 @@ func NewEncodedConn(c *Config) (*nats.EncodedConn, error) {
 		return nil, err
 	}
 
-	encoded, err := nats.NewEncodedConn(nc, nats.JSON_ENCODER)
+	encoderName := c.Encoder
+	if encoderName == "" {
+		encoderName = nats.JSON_ENCODER
+	}
+
+	encoded, err := nats.NewEncodedConn(nc, encoderName)
 	if err != nil {
 		return nil, err
 	} else {
-		c.Logger.Infow("Successfully created nats.EncodedConn", "broker", c.Broker)
+		c.Logger.Infow("Successfully created nats.EncodedConn", "broker", c.Broker, "encoder", encoderName)
 	}
 
 	return encoded, nil
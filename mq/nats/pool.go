@@ -0,0 +1,100 @@
+package natsmq
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/nats-io/nats.go"
+)
+
+// ConnPool maintains several independent *nats.Conn instances and
+// load-balances publishes across them round-robin. A single shared
+// connection serializes every publish through one set of NATS client
+// buffers; spreading load over dedicated per-peer connections reduces that
+// contention under high publish throughput.
+type ConnPool struct {
+	conns []*nats.Conn
+	next  uint64
+}
+
+// NewConnPool dials c.PoolSize independent connections (default 1 when
+// unset), each set up with setupDefaultNatsConnOptions like NewConn.
+func NewConnPool(c *Config) (*ConnPool, error) {
+	size := c.PoolSize
+	if size <= 0 {
+		size = 1
+	}
+
+	lg := c.Logger.Named("nats-pool")
+
+	pool := &ConnPool{conns: make([]*nats.Conn, 0, size)}
+	for i := 0; i < size; i++ {
+		opts := setupDefaultNatsConnOptions(lg, append([]nats.Option(nil), c.NatsConn...), nil)
+		nc, err := nats.Connect(c.Broker, opts...)
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("nats pool: conn %d/%d: %w", i+1, size, err)
+		}
+		pool.conns = append(pool.conns, nc)
+	}
+
+	lg.Infow("Initialized nats connection pool", "broker", c.Broker, "size", size)
+	return pool, nil
+}
+
+// Get returns the next connection in round-robin order, for callers that
+// need the raw *nats.Conn.
+func (p *ConnPool) Get() *nats.Conn {
+	n := atomic.AddUint64(&p.next, 1)
+	return p.conns[n%uint64(len(p.conns))]
+}
+
+// Publish publishes data on subject using the next connection in
+// round-robin order.
+func (p *ConnPool) Publish(subject string, data []byte) error {
+	return p.Get().Publish(subject, data)
+}
+
+// PublishAsync is an alias for Publish: core NATS publishes are already
+// fire-and-forget, buffered on the client and flushed in the background, so
+// there's no separate blocking variant to avoid here. It exists so pool
+// callers read the same as StanConn/JetStreamConn's Send(Async)Message
+// pairs.
+func (p *ConnPool) PublishAsync(subject string, data []byte) error {
+	return p.Publish(subject, data)
+}
+
+// ConnStats reports the metrics nats.Conn.Stats() exposes for one pooled
+// connection.
+type ConnStats struct {
+	OutMsgs    uint64
+	Reconnects uint64
+}
+
+// Stats returns one ConnStats entry per pooled connection, in pool order.
+func (p *ConnPool) Stats() []ConnStats {
+	stats := make([]ConnStats, len(p.conns))
+	for i, nc := range p.conns {
+		s := nc.Stats()
+		stats[i] = ConnStats{OutMsgs: s.OutMsgs, Reconnects: s.Reconnects}
+	}
+	return stats
+}
+
+// Healthz reports a non-nil error if any pooled connection is down,
+// suitable for wiring into an HTTP readiness probe.
+func (p *ConnPool) Healthz() error {
+	for i, nc := range p.conns {
+		if !nc.IsConnected() {
+			return fmt.Errorf("nats pool: conn %d/%d not connected", i+1, len(p.conns))
+		}
+	}
+	return nil
+}
+
+// Close closes every connection in the pool.
+func (p *ConnPool) Close() {
+	for _, nc := range p.conns {
+		nc.Close()
+	}
+}
@@ -0,0 +1,101 @@
+package natsmq
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+func TestConnPoolGetRoundRobins(t *testing.T) {
+	pool := &ConnPool{conns: []*nats.Conn{{}, {}, {}}}
+
+	seen := make(map[*nats.Conn]int)
+	for i := 0; i < 9; i++ {
+		seen[pool.Get()]++
+	}
+
+	for i, nc := range pool.conns {
+		if seen[nc] != 3 {
+			t.Errorf("conn %d got %d picks, want 3", i, seen[nc])
+		}
+	}
+}
+
+// startBenchServer starts an embedded NATS server on a random free port and
+// returns its client URL, shutting it down when the benchmark completes.
+func startBenchServer(b *testing.B) string {
+	opts := &server.Options{Host: "127.0.0.1", Port: -1}
+	s, err := server.NewServer(opts)
+	if err != nil {
+		b.Fatalf("server.NewServer() error = %v", err)
+	}
+
+	go s.Start()
+	if !s.ReadyForConnections(5 * time.Second) {
+		b.Fatal("embedded nats-server did not become ready in time")
+	}
+	b.Cleanup(s.Shutdown)
+
+	return s.ClientURL()
+}
+
+// benchmarkPublish publishes b.N messages through pool, sized poolSize, and
+// reports allocation/throughput stats alongside it.
+func benchmarkPublish(b *testing.B, poolSize int) {
+	broker := startBenchServer(b)
+
+	pool, err := NewConnPool(&Config{
+		Broker:   broker,
+		PoolSize: poolSize,
+		Logger:   zap.NewNop().Sugar(),
+	})
+	if err != nil {
+		b.Fatalf("NewConnPool() error = %v", err)
+	}
+	b.Cleanup(pool.Close)
+
+	payload := []byte("benchmark-payload")
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(payload)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := pool.Publish("bench.pool", payload); err != nil {
+			b.Fatalf("Publish() error = %v", err)
+		}
+	}
+}
+
+// BenchmarkConnPoolPublishSingleConn measures publish throughput through a
+// pool sized to a single shared connection, the baseline to compare
+// BenchmarkConnPoolPublishPooled against.
+func BenchmarkConnPoolPublishSingleConn(b *testing.B) {
+	benchmarkPublish(b, 1)
+}
+
+// BenchmarkConnPoolPublishPooled measures publish throughput spread across
+// 8 pooled connections to the same broker, demonstrating the gain ConnPool
+// is meant to provide over a single shared connection.
+func BenchmarkConnPoolPublishPooled(b *testing.B) {
+	benchmarkPublish(b, 8)
+}
+
+// BenchmarkConnPoolGet measures the round-robin selection overhead alone,
+// against disconnected placeholder connections. It doesn't exercise a real
+// broker, so it speaks only to ConnPool's own bookkeeping cost; see
+// BenchmarkConnPoolPublishSingleConn/Pooled above for actual publish
+// throughput against a real broker.
+func BenchmarkConnPoolGet(b *testing.B) {
+	pool := &ConnPool{conns: make([]*nats.Conn, 8)}
+	for i := range pool.conns {
+		pool.conns[i] = &nats.Conn{}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = pool.Get()
+	}
+}
@@ -0,0 +1,78 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/opentracing/opentracing-go"
+)
+
+// JetStreamPubSub implements PubSub on top of NATS JetStream, trading the
+// fire-and-forget semantics of NatsPubSub for an acked, at-least-once
+// publish and manually-acked subscriptions: a handler error naks the message
+// for redelivery. Subscribe sets no durable name of its own.
+type JetStreamPubSub struct {
+	js     nats.JetStreamContext
+	tracer opentracing.Tracer
+}
+
+// NewJetStreamPubSub wraps an already-configured JetStream context. tracer
+// may be nil, in which case Subscribe invokes handler without starting a
+// span.
+func NewJetStreamPubSub(js nats.JetStreamContext, tracer opentracing.Tracer) *JetStreamPubSub {
+	return &JetStreamPubSub{js: js, tracer: tracer}
+}
+
+func (j *JetStreamPubSub) Publish(ctx context.Context, channel string, msg *Message) error {
+	span := injectSpan(ctx, j.tracer, "jetstream.publish "+channel, msg)
+	if span != nil {
+		defer span.Finish()
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+
+	_, err = j.js.Publish(channel, payload)
+	return err
+}
+
+func (j *JetStreamPubSub) Subscribe(ctx context.Context, channel, group string, handler Handler) error {
+	cb := func(m *nats.Msg) {
+		var msg Message
+		if err := json.Unmarshal(m.Data, &msg); err != nil {
+			return
+		}
+
+		spanCtx, span := extractSpan(ctx, j.tracer, "jetstream.subscribe "+channel, &msg)
+		if span != nil {
+			defer span.Finish()
+		}
+
+		if err := handler(spanCtx, &msg); err != nil {
+			_ = m.Nak()
+			return
+		}
+		_ = m.Ack()
+	}
+
+	opts := []nats.SubOpt{nats.ManualAck()}
+
+	var err error
+	if group != "" {
+		_, err = j.js.QueueSubscribe(channel, group, cb, opts...)
+	} else {
+		_, err = j.js.Subscribe(channel, cb, opts...)
+	}
+
+	return err
+}
+
+// Close is a no-op: JetStreamContext has no lifecycle of its own, it rides
+// on the underlying *nats.Conn's.
+func (j *JetStreamPubSub) Close() error {
+	return nil
+}
@@ -0,0 +1,59 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// MemoryPubSub is an in-process PubSub implementation backed by nothing
+// more than a mutex-guarded map. It's intended for unit tests that want to
+// exercise publish/subscribe wiring without standing up a real NATS server.
+type MemoryPubSub struct {
+	mu   sync.RWMutex
+	subs map[string][]Handler
+}
+
+// NewMemoryPubSub returns a ready-to-use in-memory bus.
+func NewMemoryPubSub() *MemoryPubSub {
+	return &MemoryPubSub{
+		subs: make(map[string][]Handler),
+	}
+}
+
+// Publish delivers msg to every handler subscribed to channel. A handler
+// returning an error doesn't stop delivery to the rest: every subscriber
+// still gets its copy, and the errors are joined together in the result.
+func (m *MemoryPubSub) Publish(ctx context.Context, channel string, msg *Message) error {
+	m.mu.RLock()
+	handlers := append([]Handler(nil), m.subs[channel]...)
+	m.mu.RUnlock()
+
+	var errs error
+	for _, h := range handlers {
+		if err := h(ctx, msg); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// Subscribe registers handler for channel. group is accepted for interface
+// compatibility but ignored: every subscriber gets its own copy since there's
+// no notion of competing consumers in-process.
+func (m *MemoryPubSub) Subscribe(ctx context.Context, channel, group string, handler Handler) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.subs[channel] = append(m.subs[channel], handler)
+	return nil
+}
+
+func (m *MemoryPubSub) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.subs = nil
+	return nil
+}
@@ -0,0 +1,69 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryPubSubPublishSubscribe(t *testing.T) {
+	bus := NewMemoryPubSub()
+
+	received := make(chan *Message, 1)
+	err := bus.Subscribe(context.Background(), "orders.created", "", func(_ context.Context, msg *Message) error {
+		received <- msg
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	want := &Message{Subject: "orders.created", Payload: []byte("hello"), CreatedAt: time.Now()}
+	if err := bus.Publish(context.Background(), "orders.created", want); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	select {
+	case got := <-received:
+		if string(got.Payload) != "hello" {
+			t.Errorf("Payload = %q, want %q", got.Payload, "hello")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler was not invoked")
+	}
+}
+
+func TestMemoryPubSubPublishDeliversDespiteHandlerError(t *testing.T) {
+	bus := NewMemoryPubSub()
+
+	boom := errors.New("boom")
+	if err := bus.Subscribe(context.Background(), "ch", "", func(_ context.Context, _ *Message) error {
+		return boom
+	}); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	received := make(chan *Message, 1)
+	if err := bus.Subscribe(context.Background(), "ch", "", func(_ context.Context, msg *Message) error {
+		received <- msg
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	want := &Message{Subject: "ch", Payload: []byte("hello")}
+	err := bus.Publish(context.Background(), "ch", want)
+	if !errors.Is(err, boom) {
+		t.Errorf("Publish() error = %v, want it to wrap %v", err, boom)
+	}
+
+	select {
+	case got := <-received:
+		if string(got.Payload) != "hello" {
+			t.Errorf("Payload = %q, want %q", got.Payload, "hello")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("second subscriber was not invoked after the first handler errored")
+	}
+}
@@ -0,0 +1,15 @@
+package pubsub
+
+import "time"
+
+// Message is the transport-agnostic envelope exchanged between Publishers
+// and Subscribers. Every PubSub implementation in this package marshals to
+// and from this type so callers can swap the underlying broker (NATS core,
+// JetStream, an in-memory bus) without changing call sites.
+type Message struct {
+	Subject   string            `json:"subject"`
+	Payload   []byte            `json:"payload"`
+	Publisher string            `json:"publisher,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
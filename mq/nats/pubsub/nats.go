@@ -0,0 +1,63 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/opentracing/opentracing-go"
+)
+
+// NatsPubSub implements PubSub on top of core NATS (at-most-once delivery,
+// no persistence). Use JetStreamPubSub when durability or redelivery is
+// required.
+type NatsPubSub struct {
+	conn   *nats.Conn
+	tracer opentracing.Tracer
+}
+
+// NewNatsPubSub wraps an already-established core NATS connection. tracer
+// may be nil, in which case Subscribe invokes handler without starting a
+// span.
+func NewNatsPubSub(conn *nats.Conn, tracer opentracing.Tracer) *NatsPubSub {
+	return &NatsPubSub{conn: conn, tracer: tracer}
+}
+
+func (n *NatsPubSub) Publish(ctx context.Context, channel string, msg *Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+
+	return n.conn.Publish(channel, payload)
+}
+
+func (n *NatsPubSub) Subscribe(ctx context.Context, channel, group string, handler Handler) error {
+	cb := func(m *nats.Msg) {
+		var msg Message
+		if err := json.Unmarshal(m.Data, &msg); err != nil {
+			return
+		}
+
+		spanCtx, span := extractSpan(ctx, n.tracer, "nats.subscribe "+channel, &msg)
+		if span != nil {
+			defer span.Finish()
+		}
+		_ = handler(spanCtx, &msg)
+	}
+
+	var err error
+	if group != "" {
+		_, err = n.conn.QueueSubscribe(channel, group, cb)
+	} else {
+		_, err = n.conn.Subscribe(channel, cb)
+	}
+
+	return err
+}
+
+func (n *NatsPubSub) Close() error {
+	n.conn.Close()
+	return nil
+}
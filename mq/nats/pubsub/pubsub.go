@@ -0,0 +1,39 @@
+package pubsub
+
+import "context"
+
+// Handler processes a single Message delivered to a subscription.
+type Handler func(ctx context.Context, msg *Message) error
+
+// Publisher publishes a Message to a channel (NATS subject).
+type Publisher interface {
+	Publish(ctx context.Context, channel string, msg *Message) error
+}
+
+// AckHandler is invoked once an async publish completes, successfully or
+// not, with the transport-assigned id of the publish (e.g. a STAN guid).
+type AckHandler func(id string, err error)
+
+// AsyncPublisher is implemented by PubSub backends whose transport supports
+// a non-blocking publish with out-of-band ack delivery (currently STAN's
+// PublishAsync). Not every PubSub implements it: callers should type-assert
+// and fall back to Publish when it's absent.
+type AsyncPublisher interface {
+	PublishAsync(ctx context.Context, channel string, msg *Message, ack AckHandler) (string, error)
+}
+
+// Subscriber subscribes a Handler to a channel. When group is non-empty,
+// delivery is load-balanced across every subscriber sharing that group
+// (a NATS queue group), otherwise every subscriber receives its own copy.
+type Subscriber interface {
+	Subscribe(ctx context.Context, channel, group string, handler Handler) error
+}
+
+// PubSub combines Publisher and Subscriber behind a single closable
+// connection, letting callers depend on one interface regardless of the
+// underlying transport.
+type PubSub interface {
+	Publisher
+	Subscriber
+	Close() error
+}
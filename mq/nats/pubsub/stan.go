@@ -0,0 +1,78 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/stan.go"
+	"github.com/opentracing/opentracing-go"
+)
+
+// StanPubSub adapts a nats-streaming connection to the PubSub interface so
+// existing STAN-based callers can be migrated onto the transport-agnostic
+// API ahead of a JetStream cutover.
+type StanPubSub struct {
+	conn   stan.Conn
+	tracer opentracing.Tracer
+}
+
+// NewStanPubSub wraps an already-established STAN connection. tracer may be
+// nil, in which case Subscribe invokes handler without starting a span.
+func NewStanPubSub(conn stan.Conn, tracer opentracing.Tracer) *StanPubSub {
+	return &StanPubSub{conn: conn, tracer: tracer}
+}
+
+func (s *StanPubSub) Publish(ctx context.Context, channel string, msg *Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+
+	return s.conn.Publish(channel, payload)
+}
+
+// PublishAsync publishes msg to channel without waiting for the ack; ack, if
+// non-nil, is invoked once the broker confirms or rejects the publish. It
+// returns the guid STAN assigns the publish.
+func (s *StanPubSub) PublishAsync(ctx context.Context, channel string, msg *Message, ack AckHandler) (string, error) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return "", fmt.Errorf("marshal message: %w", err)
+	}
+
+	var cb stan.AckHandler
+	if ack != nil {
+		cb = func(nid string, ackErr error) { ack(nid, ackErr) }
+	}
+
+	return s.conn.PublishAsync(channel, payload, cb)
+}
+
+func (s *StanPubSub) Subscribe(ctx context.Context, channel, group string, handler Handler) error {
+	cb := func(m *stan.Msg) {
+		var msg Message
+		if err := json.Unmarshal(m.Data, &msg); err != nil {
+			return
+		}
+
+		spanCtx, span := extractSpan(ctx, s.tracer, "stan.subscribe "+channel, &msg)
+		if span != nil {
+			defer span.Finish()
+		}
+		_ = handler(spanCtx, &msg)
+	}
+
+	var err error
+	if group != "" {
+		_, err = s.conn.QueueSubscribe(channel, group, cb)
+	} else {
+		_, err = s.conn.Subscribe(channel, cb)
+	}
+
+	return err
+}
+
+func (s *StanPubSub) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,61 @@
+package pubsub
+
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// injectSpan starts a span for operation, parented off any span already in
+// ctx, and writes its propagation headers into msg.Headers so the receiving
+// Subscribe can extract it. It returns nil when tracer is nil.
+//
+// This mirrors natsmq's own injectSpan/extractSpan pair one layer up: that
+// package can't be imported here (natsmq already imports pubsub), so each
+// side of the publish/subscribe boundary carries its own small copy.
+func injectSpan(ctx context.Context, tracer opentracing.Tracer, operation string, msg *Message) opentracing.Span {
+	if tracer == nil {
+		return nil
+	}
+
+	var span opentracing.Span
+	if parent := opentracing.SpanFromContext(ctx); parent != nil {
+		span = tracer.StartSpan(operation, opentracing.ChildOf(parent.Context()))
+	} else {
+		span = tracer.StartSpan(operation)
+	}
+
+	carrier := opentracing.TextMapCarrier{}
+	if err := tracer.Inject(span.Context(), opentracing.TextMap, carrier); err == nil {
+		if msg.Headers == nil {
+			msg.Headers = make(map[string]string, len(carrier))
+		}
+		for k, v := range carrier {
+			msg.Headers[k] = v
+		}
+	}
+
+	return span
+}
+
+// extractSpan starts a child span for operation from any trace headers
+// found on msg and returns a context carrying it, ready to pass to a
+// Handler. It returns ctx unchanged and a nil span when tracer is nil or
+// msg carries no trace headers.
+//
+// This mirrors natsmq's own injectSpan/extractSpan pair one layer up: that
+// package can't be imported here (natsmq already imports pubsub), so each
+// side of the publish/subscribe boundary carries its own small copy.
+func extractSpan(ctx context.Context, tracer opentracing.Tracer, operation string, msg *Message) (context.Context, opentracing.Span) {
+	if tracer == nil || len(msg.Headers) == 0 {
+		return ctx, nil
+	}
+
+	spanCtx, err := tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier(msg.Headers))
+	if err != nil {
+		return ctx, nil
+	}
+
+	span := tracer.StartSpan(operation, opentracing.ChildOf(spanCtx))
+	return opentracing.ContextWithSpan(ctx, span), span
+}
@@ -1,21 +1,28 @@
 package natsmq
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nuid"
 	"github.com/nats-io/stan.go"
 	"github.com/opentracing/opentracing-go"
 	"go.uber.org/zap"
 	"time"
+
+	"github.com/rovergulf/utils/mq/nats/pubsub"
 )
 
 type StanConn struct {
 	clientId string
 	client   stan.Conn
+	nc       *nats.Conn
+	bus      pubsub.PubSub
 	tracer   opentracing.Tracer
 	logger   *zap.SugaredLogger
 	nuid     *nuid.NUID
+	metrics  *metrics
 }
 
 func NewStanConn(c *Config) (*StanConn, error) {
@@ -24,6 +31,7 @@ func NewStanConn(c *Config) (*StanConn, error) {
 		logger:   c.Logger.Named("nats-streaming"),
 		nuid:     nuid.New(),
 		clientId: fmt.Sprintf("%s-%d", c.ClientId, time.Now().Unix()),
+		metrics:  newMetrics(c.MetricsRegistry, "stan"),
 	}
 
 	nc, err := NewConn(c)
@@ -51,6 +59,8 @@ func NewStanConn(c *Config) (*StanConn, error) {
 		return nil, err
 	}
 	s.client = sc
+	s.nc = nc
+	s.bus = pubsub.NewStanPubSub(sc, c.Tracer)
 
 	s.logger.Infow("Initialized nats-streaming conn",
 		"broker", c.Broker, "cluster_id", c.ClusterId, "client_id", c.ClientId)
@@ -67,6 +77,15 @@ func (sc *StanConn) Stop() {
 	}
 }
 
+// Healthz reports a non-nil error when the underlying NATS connection is
+// down, suitable for wiring into an HTTP readiness probe.
+func (sc *StanConn) Healthz() error {
+	if sc.nc == nil || !sc.nc.IsConnected() {
+		return fmt.Errorf("stan: not connected")
+	}
+	return nil
+}
+
 func (sc *StanConn) DefaultAckHandler(nid string, err error) {
 	if err != nil {
 		sc.logger.Errorw("Error publishing message", "guid", nid, "err", err)
@@ -75,8 +94,16 @@ func (sc *StanConn) DefaultAckHandler(nid string, err error) {
 	}
 }
 
+// SendMessage publishes data to channel.
+//
+// Deprecated: use SendMessageContext so the publish can be linked into a
+// caller's distributed trace.
 func (sc *StanConn) SendMessage(channel string, data interface{}) {
-	if sc.client == nil {
+	sc.SendMessageContext(context.Background(), channel, data)
+}
+
+func (sc *StanConn) SendMessageContext(ctx context.Context, channel string, data interface{}) {
+	if sc.bus == nil {
 		return
 	}
 
@@ -86,7 +113,22 @@ func (sc *StanConn) SendMessage(channel string, data interface{}) {
 		return
 	}
 
-	if err := sc.client.Publish(channel, payload); err != nil {
+	msg := &pubsub.Message{
+		Subject:   channel,
+		Payload:   payload,
+		Publisher: sc.clientId,
+		CreatedAt: time.Now(),
+	}
+
+	span := injectSpan(ctx, sc.tracer, "stan.publish "+channel, msg)
+	if span != nil {
+		defer span.Finish()
+	}
+
+	start := time.Now()
+	err = sc.bus.Publish(ctx, channel, msg)
+	sc.metrics.observePublish(channel, start, err)
+	if err != nil {
 		sc.logger.Errorw("Error publishing message",
 			"client_id", sc.clientId, "chan", channel, "err", err)
 	} else {
@@ -94,8 +136,18 @@ func (sc *StanConn) SendMessage(channel string, data interface{}) {
 	}
 }
 
+// SendAsyncMessage publishes data to channel without waiting for the ack.
+//
+// Deprecated: use SendAsyncMessageContext so the publish can be linked into
+// a caller's distributed trace.
 func (sc *StanConn) SendAsyncMessage(channel string, data interface{}) {
-	if sc.client == nil {
+	sc.SendAsyncMessageContext(context.Background(), channel, data)
+}
+
+func (sc *StanConn) SendAsyncMessageContext(ctx context.Context, channel string, data interface{}) {
+	ap, ok := sc.bus.(pubsub.AsyncPublisher)
+	if !ok {
+		sc.logger.Errorw("Underlying PubSub does not support async publish", "chan", channel)
 		return
 	}
 
@@ -105,7 +157,28 @@ func (sc *StanConn) SendAsyncMessage(channel string, data interface{}) {
 		return
 	}
 
-	nid, err := sc.client.PublishAsync(channel, payload, sc.DefaultAckHandler)
+	msg := &pubsub.Message{
+		Subject:   channel,
+		Payload:   payload,
+		Publisher: sc.clientId,
+		CreatedAt: time.Now(),
+	}
+
+	span := injectSpan(ctx, sc.tracer, "stan.publish_async "+channel, msg)
+	if span != nil {
+		defer span.Finish()
+	}
+
+	start := time.Now()
+	ackHandler := func(nid string, ackErr error) {
+		if ackErr == nil {
+			sc.metrics.observeAck(channel, start)
+		}
+		sc.DefaultAckHandler(nid, ackErr)
+	}
+
+	nid, err := ap.PublishAsync(ctx, channel, msg, ackHandler)
+	sc.metrics.observePublish(channel, start, err)
 	if err != nil {
 		sc.logger.Errorw("Error publishing",
 			"chan", channel, "client_id", sc.clientId, "err", err)
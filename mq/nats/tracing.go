@@ -0,0 +1,57 @@
+package natsmq
+
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/rovergulf/utils/mq/nats/pubsub"
+)
+
+// injectSpan starts a child span for operation from any span found on ctx
+// and, when tracer is non-nil, injects its SpanContext into msg.Headers so
+// the receiving side can continue the trace even over transports (STAN,
+// core NATS pre-2.2) that carry the whole Message as a JSON blob rather
+// than native headers. The returned span, if any, must be finished by the
+// caller.
+func injectSpan(ctx context.Context, tracer opentracing.Tracer, operation string, msg *pubsub.Message) opentracing.Span {
+	if tracer == nil {
+		return nil
+	}
+
+	var span opentracing.Span
+	if parent := opentracing.SpanFromContext(ctx); parent != nil {
+		span = tracer.StartSpan(operation, opentracing.ChildOf(parent.Context()))
+	} else {
+		span = tracer.StartSpan(operation)
+	}
+
+	carrier := opentracing.TextMapCarrier{}
+	if err := tracer.Inject(span.Context(), opentracing.TextMap, carrier); err == nil {
+		if msg.Headers == nil {
+			msg.Headers = make(map[string]string, len(carrier))
+		}
+		for k, v := range carrier {
+			msg.Headers[k] = v
+		}
+	}
+
+	return span
+}
+
+// extractSpan reverses injectSpan: given a received Message it starts a
+// child span for operation from any trace headers found on it and returns a
+// context carrying that span, ready to pass into a Handler.
+func extractSpan(ctx context.Context, tracer opentracing.Tracer, operation string, msg *pubsub.Message) (context.Context, opentracing.Span) {
+	if tracer == nil || len(msg.Headers) == 0 {
+		return ctx, nil
+	}
+
+	spanCtx, err := tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier(msg.Headers))
+	if err != nil {
+		return ctx, nil
+	}
+
+	span := tracer.StartSpan(operation, opentracing.ChildOf(spanCtx))
+	return opentracing.ContextWithSpan(ctx, span), span
+}